@@ -0,0 +1,79 @@
+package shutil
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// DefaultCopyBufferSize is the chunk size used to copy file data when
+// CopyOptions.BufferSize is left at zero.
+const DefaultCopyBufferSize = 1 << 20 // 1 MiB
+
+// transferData copies srcInfo's data from fsrc to fdst. When no Progress
+// callback is set and both ends are *os.File, it first tries the
+// platform's zero-copy fast path (see copyFileRange); otherwise, and
+// whenever the fast path doesn't apply, it falls back to a chunked copy
+// that respects ctx and reports progress.
+func transferData(ctx context.Context, fdst io.Writer, fsrc io.Reader, srcInfo os.FileInfo, options *CopyOptions) (int64, error) {
+	var progress func(written, total int64)
+	var bufferSize int64
+	if options != nil {
+		progress = options.Progress
+		bufferSize = options.BufferSize
+	}
+
+	if progress == nil {
+		if sf, ok := fsrc.(*os.File); ok {
+			if df, ok := fdst.(*os.File); ok {
+				if written, handled, err := copyFileRange(sf, df, srcInfo); handled {
+					return written, err
+				}
+			}
+		}
+	}
+
+	return copyChunked(ctx, fdst, fsrc, bufferSize, progress, srcInfo.Size())
+}
+
+// copyChunked copies from r to w in chunks of bufferSize (or
+// DefaultCopyBufferSize when bufferSize <= 0), checking ctx for
+// cancellation between chunks and reporting progress via progress, if set.
+func copyChunked(ctx context.Context, w io.Writer, r io.Reader, bufferSize int64, progress func(written, total int64), total int64) (int64, error) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultCopyBufferSize
+	}
+
+	buf := make([]byte, bufferSize)
+	var written int64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return written, ctx.Err()
+		default:
+		}
+
+		n, rerr := r.Read(buf)
+		if n > 0 {
+			wn, werr := w.Write(buf[:n])
+			written += int64(wn)
+			if progress != nil {
+				progress(written, total)
+			}
+			if werr != nil {
+				return written, werr
+			}
+			if wn != n {
+				return written, io.ErrShortWrite
+			}
+		}
+
+		if rerr != nil {
+			if rerr == io.EOF {
+				return written, nil
+			}
+			return written, rerr
+		}
+	}
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package shutil
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// copyFileRange attempts a zero-copy transfer from src to dst when both
+// are regular files on the same filesystem. It hands the transfer to
+// io.Copy, which (via os.File's ReaderFrom) already drives the
+// copy_file_range(2) syscall on Linux and falls back internally on
+// EXDEV/ENOSYS; this wrapper's own fallback below is a backstop for any
+// error that surfaces anyway. handled reports whether the fast path was
+// applicable at all; when it's false, the caller should run a generic
+// chunked copy instead.
+func copyFileRange(src, dst *os.File, srcInfo os.FileInfo) (written int64, handled bool, err error) {
+	if !srcInfo.Mode().IsRegular() {
+		return 0, false, nil
+	}
+
+	dstInfo, statErr := dst.Stat()
+	if statErr != nil || !dstInfo.Mode().IsRegular() {
+		return 0, false, nil
+	}
+
+	srcStat, ok1 := srcInfo.Sys().(*syscall.Stat_t)
+	dstStat, ok2 := dstInfo.Sys().(*syscall.Stat_t)
+	if !ok1 || !ok2 || srcStat.Dev != dstStat.Dev {
+		return 0, false, nil
+	}
+
+	written, err = io.Copy(dst, src)
+	if err != nil {
+		var errno syscall.Errno
+		if errors.As(err, &errno) && (errno == syscall.ENOSYS || errno == syscall.EXDEV) {
+			return 0, false, nil
+		}
+		return written, true, err
+	}
+
+	return written, true, nil
+}
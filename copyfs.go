@@ -0,0 +1,161 @@
+package shutil
+
+import (
+	"context"
+	"log"
+	"os"
+)
+
+// Copy data from src to dst, reading through srcFS and writing through
+// dstFS. CopyFS(srcFS, dstFS, src, dst, options) is equivalent to
+// CopyFSContext(context.Background(), srcFS, dstFS, src, dst, options).
+//
+// If follow_symlinks is not set and src is a symbolic link, a new
+// symlink will be created instead of copying the file it points to.
+func CopyFS(srcFS FileSystem, dstFS WritableFileSystem, src, dst string, options *CopyOptions) (string, error) {
+	return CopyFSContext(context.Background(), srcFS, dstFS, src, dst, options)
+}
+
+// CopyFSContext is CopyFS with an explicit context.Context: the context
+// is checked between chunks of the chunked copy, so a long transfer can
+// be cancelled cleanly. The zero-copy fast path that transferData takes
+// on Linux (no Progress callback, regular files on one filesystem)
+// copies in a single pass and does not check the context.
+func CopyFSContext(ctx context.Context, srcFS FileSystem, dstFS WritableFileSystem, src, dst string, options *CopyOptions) (string, error) {
+	followSymlinks := true
+	if options != nil {
+		followSymlinks = options.FollowSymlinks
+	}
+
+	statFn := srcFS.Stat
+	if !followSymlinks {
+		statFn = srcFS.Lstat
+	}
+
+	srcInfo, err := statFn(src)
+	if err != nil {
+		return "", err
+	}
+
+	if options != nil && options.PreCallback != nil {
+		if err := options.PreCallback(src, dst, srcInfo); err != nil {
+			if err == ErrSkip {
+				return dst, nil
+			}
+			return "", err
+		}
+	}
+
+	if srcInfo.Mode()&os.ModeNamedPipe == os.ModeNamedPipe {
+		return "", &SpecialFileError{src}
+	}
+
+	dstInfo, err := statIfPossible(dstFS, dst)
+	if err == nil {
+		if os.SameFile(srcInfo, dstInfo) {
+			return "", &SameFileError{src, dst}
+		}
+		if dstInfo.Mode()&os.ModeNamedPipe == os.ModeNamedPipe {
+			return "", &SpecialFileError{dst}
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if !followSymlinks && ((srcInfo.Mode() & os.ModeSymlink) == os.ModeSymlink) {
+		srcOrigin, err := srcFS.Readlink(src)
+		if err != nil {
+			return "", err
+		}
+
+		if err := dstFS.Symlink(srcOrigin, dst); err != nil {
+			return "", err
+		}
+	} else {
+		fsrc, err := srcFS.Open(src)
+		if err != nil {
+			return "", err
+		}
+		defer fsrc.Close()
+
+		fdst, err := dstFS.Create(dst)
+		if err != nil {
+			return "", err
+		}
+		defer fdst.Close()
+
+		size, err := transferData(ctx, fdst, fsrc, srcInfo, options)
+		if err != nil {
+			return "", err
+		}
+
+		if size != srcInfo.Size() {
+			log.Printf("%s: %d/%d copied", src, size, srcInfo.Size())
+			return "", &CopyNotCompleteError{src, dst}
+		}
+	}
+
+	if options != nil && options.PostCallback != nil {
+		if err := options.PostCallback(src, dst, srcInfo); err != nil {
+			return "", err
+		}
+	}
+
+	return dst, nil
+}
+
+// Copy data and all stat info CopyFS can reach ("cp -p src dst") via
+// srcFS/dstFS. Copy2(src, dst, options) is equivalent to
+// Copy2FS(OSFileSystem{}, OSFileSystem{}, src, dst, options).
+//
+// The destination may be a directory.
+func Copy2FS(srcFS FileSystem, dstFS WritableFileSystem, src, dst string, options *CopyOptions) (string, error) {
+	if dstInfo, err := statIfPossible(dstFS, dst); err == nil && dstInfo.IsDir() {
+		dst = fsPathJoin(dstFS, dst, fsPathBase(srcFS, src))
+	}
+
+	_, err := CopyFS(srcFS, dstFS, src, dst, options)
+	if err != nil {
+		return "", err
+	}
+
+	if err := copyStatFS(srcFS, dstFS, src, dst, options); err != nil {
+		return "", err
+	}
+
+	return dst, nil
+}
+
+// copyStatFS copies metadata from src to dst via srcFS/dstFS. When both
+// are the real, local filesystem it defers to CopyStat for full fidelity
+// (ownership, xattrs); otherwise it copies what the generic FileSystem /
+// WritableFileSystem interfaces expose: mode bits and modification time.
+func copyStatFS(srcFS FileSystem, dstFS WritableFileSystem, src, dst string, options *CopyOptions) error {
+	if _, srcOS := srcFS.(OSFileSystem); srcOS {
+		if _, dstOS := dstFS.(OSFileSystem); dstOS {
+			return CopyStat(src, dst, options)
+		}
+	}
+
+	followSymlinks := true
+	if options != nil {
+		followSymlinks = options.FollowSymlinks
+	}
+
+	statFn := srcFS.Stat
+	if !followSymlinks {
+		statFn = srcFS.Lstat
+	}
+
+	srcInfo, err := statFn(src)
+	if err != nil {
+		return err
+	}
+
+	if err := dstFS.Chmod(dst, srcInfo.Mode()); err != nil {
+		return err
+	}
+
+	mtime := srcInfo.ModTime()
+	return dstFS.Chtimes(dst, mtime, mtime)
+}
@@ -0,0 +1,52 @@
+package shutil
+
+import (
+	"embed"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//go:embed testdata/embedroot
+var embedRoot embed.FS
+
+func TestCopyFSFromEmbedFS(t *testing.T) {
+	srcFS := FromFS(embedRoot)
+	dstDir := t.TempDir()
+	dst := filepath.Join(dstDir, "a.txt")
+
+	if _, err := CopyFS(srcFS, OSFileSystem{}, "testdata/embedroot/a.txt", dst, nil); err != nil {
+		t.Fatalf("CopyFS: %v", err)
+	}
+
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "hello\n" {
+		t.Fatalf("dst content = %q, want %q", got, "hello\n")
+	}
+}
+
+func TestCopyTreeFSFromEmbedFS(t *testing.T) {
+	srcFS := FromFS(embedRoot)
+	dstRoot := t.TempDir()
+	dst := filepath.Join(dstRoot, "tree")
+
+	if _, err := CopyTreeFS(srcFS, OSFileSystem{}, "testdata/embedroot", dst, nil); err != nil {
+		t.Fatalf("CopyTreeFS: %v", err)
+	}
+
+	for rel, want := range map[string]string{
+		"a.txt":     "hello\n",
+		"sub/b.txt": "world\n",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, rel))
+		if err != nil {
+			t.Fatalf("ReadFile(%s): %v", rel, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", rel, got, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package shutil
+
+import "os"
+
+// CopyStatOptions selects which pieces of metadata CopyStat copies. When
+// CopyOptions.Stat is nil, every piece is copied; a non-nil
+// CopyStatOptions copies only the pieces whose fields are true, and a
+// field left out of a struct literal is false.
+type CopyStatOptions struct {
+	Times bool
+	Mode  bool
+	Owner bool
+
+	// Xattrs has no effect: copying extended attributes needs
+	// Llistxattr/Lgetxattr/Lsetxattr, which only exist in
+	// golang.org/x/sys/unix, not the standard library syscall package,
+	// and this module does not require golang.org/x/sys.
+	// Setting this true does not copy any xattrs; see copyXattrs.
+	Xattrs bool
+}
+
+var defaultCopyStatOptions = CopyStatOptions{Times: true, Mode: true, Owner: true, Xattrs: true}
+
+// Copy all stat info (mode bits, atime/mtime and, on Unix, ownership)
+// from src to dst. Extended attributes are not copied on any platform;
+// see CopyStatOptions.Xattrs.
+//
+// If the optional flag `follow_symlinks` is not set, symlinks aren't followed if and
+// only if both `src` and `dst` are symlinks; in that case the metadata of
+// the link itself is copied rather than that of its target. Of that
+// metadata only the ownership is copied (on Unix): mode bits and times
+// are not set on a link.
+//
+// Which pieces of metadata are copied can be restricted via
+// options.Stat (see CopyStatOptions); by default everything is copied.
+func CopyStat(src, dst string, options *CopyOptions) error {
+	followSymlinks := true
+	statOptions := defaultCopyStatOptions
+	if options != nil {
+		followSymlinks = options.FollowSymlinks
+		if options.Stat != nil {
+			statOptions = *options.Stat
+		}
+	}
+
+	statFn := os.Stat
+	if !followSymlinks {
+		statFn = os.Lstat
+	}
+
+	srcInfo, err := statFn(src)
+	if err != nil {
+		return err
+	}
+	dstInfo, err := statFn(dst)
+	if err != nil {
+		return err
+	}
+
+	linkOnly := !followSymlinks &&
+		srcInfo.Mode()&os.ModeSymlink == os.ModeSymlink &&
+		dstInfo.Mode()&os.ModeSymlink == os.ModeSymlink
+
+	return copyStatPlatform(src, dst, srcInfo, linkOnly, statOptions)
+}
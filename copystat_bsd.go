@@ -0,0 +1,45 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package shutil
+
+import (
+	"os"
+	"syscall"
+)
+
+func copyStatPlatform(src, dst string, srcInfo os.FileInfo, linkOnly bool, opts CopyStatOptions) error {
+	if opts.Mode {
+		if linkOnly {
+			// The standard syscall package has no lchmod wrapper on any
+			// of these platforms (it lives only in golang.org/x/sys/unix,
+			// which this module does not require), so symlink mode bits
+			// aren't copied here.
+		} else if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+			return err
+		}
+	}
+
+	st, ok := srcInfo.Sys().(*syscall.Stat_t)
+
+	// os.Chtimes follows symlinks, so a link's own times aren't copied.
+	if opts.Times && !linkOnly {
+		// The Stat_t timespec fields (Atimespec/Atim/...) aren't named
+		// consistently across these platforms in the standard syscall
+		// package, so fall back to the portable ModTime() for both
+		// atime and mtime rather than risk the wrong field name.
+		mtime := srcInfo.ModTime()
+		if err := os.Chtimes(dst, mtime, mtime); err != nil {
+			return err
+		}
+	}
+
+	if opts.Owner && ok {
+		if err := os.Lchown(dst, int(st.Uid), int(st.Gid)); err != nil {
+			return err
+		}
+	}
+
+	// Extended attributes aren't copied on this platform yet.
+
+	return nil
+}
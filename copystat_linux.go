@@ -0,0 +1,45 @@
+//go:build linux
+
+package shutil
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func copyStatPlatform(src, dst string, srcInfo os.FileInfo, linkOnly bool, opts CopyStatOptions) error {
+	if opts.Mode {
+		if linkOnly {
+			// Linux has no lchmod syscall; symlink permission bits aren't
+			// meaningful, so there is nothing to copy.
+		} else if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+			return err
+		}
+	}
+
+	st, ok := srcInfo.Sys().(*syscall.Stat_t)
+
+	// os.Chtimes follows symlinks, so a link's own times aren't copied.
+	if opts.Times && ok && !linkOnly {
+		atime := time.Unix(st.Atim.Sec, st.Atim.Nsec)
+		mtime := time.Unix(st.Mtim.Sec, st.Mtim.Nsec)
+		if err := os.Chtimes(dst, atime, mtime); err != nil {
+			return err
+		}
+	}
+
+	if opts.Owner && ok {
+		if err := os.Lchown(dst, int(st.Uid), int(st.Gid)); err != nil {
+			return err
+		}
+	}
+
+	if opts.Xattrs {
+		if err := copyXattrs(src, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package shutil
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func copyStatPlatform(src, dst string, srcInfo os.FileInfo, linkOnly bool, opts CopyStatOptions) error {
+	if opts.Mode {
+		if linkOnly {
+			// Windows has no lchmod equivalent; symlink permission bits
+			// aren't meaningful, so there is nothing to copy.
+		} else if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+			return err
+		}
+	}
+
+	// os.Chtimes follows symlinks, so a link's own times aren't copied.
+	if opts.Times && !linkOnly {
+		if sysInfo, ok := srcInfo.Sys().(*syscall.Win32FileAttributeData); ok {
+			atime := time.Unix(0, sysInfo.LastAccessTime.Nanoseconds())
+			mtime := time.Unix(0, sysInfo.LastWriteTime.Nanoseconds())
+			if err := os.Chtimes(dst, atime, mtime); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Ownership and extended attributes aren't copied on Windows.
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package shutil
+
+import (
+	"os"
+)
+
+// SymlinkAction controls how CopyTree treats a symlink found in the
+// source tree.
+type SymlinkAction int
+
+const (
+	// Deep resolves the symlink and copies its target recursively, as if
+	// the link were the file or directory it points to.
+	Deep SymlinkAction = iota
+	// Shallow recreates the symlink at the destination, pointing at the
+	// same target, instead of copying what it points to.
+	Shallow
+	// Skip omits the entry from the destination tree entirely.
+	Skip
+)
+
+type CopyTreeOptions struct {
+	Symlinks               bool
+	Ignore                 func(string, []os.FileInfo) []string
+	CopyFunction           func(string, string, *CopyOptions) (string, error)
+	IgnoreDanglingSymlinks bool
+
+	// OnSymlink decides, per entry, how a symlink in the source tree is
+	// handled. When nil, Symlinks is used instead: true behaves as
+	// Shallow, false behaves as Deep.
+	OnSymlink func(src string) SymlinkAction
+
+	// PreCallback, if set, is called before each entry (file, directory
+	// or symlink) is copied. Returning ErrSkip omits that entry (and, for
+	// a directory, everything beneath it) without aborting the rest of
+	// the copy. Any other non-nil error aborts CopyTree.
+	PreCallback func(src, dst string, srcfi os.FileInfo) error
+
+	// PostCallback, if set, is called after each entry is successfully
+	// copied, so callers can chain metadata operations or emit events.
+	PostCallback func(src, dst string, srcfi os.FileInfo) error
+
+	// OnDirExists decides what CopyTree does when dst already exists as a
+	// directory. When nil, Fail is used, preserving CopyTree's documented
+	// "must not already exist" contract.
+	OnDirExists func(src, dst string) DirExistsAction
+}
+
+// DirExistsAction controls how CopyTree treats a destination directory
+// that already exists.
+type DirExistsAction int
+
+const (
+	// Fail aborts the copy with a DirExistsError.
+	Fail DirExistsAction = iota
+	// Merge copies src's children into the existing dst, overwriting
+	// files that are already there.
+	Merge
+	// Replace removes dst (via RmTree) before copying src into it fresh.
+	Replace
+)
+
+func dirExistsAction(options *CopyTreeOptions, src, dst string) DirExistsAction {
+	if options != nil && options.OnDirExists != nil {
+		return options.OnDirExists(src, dst)
+	}
+	return Fail
+}
+
+func symlinkAction(options *CopyTreeOptions, src string) SymlinkAction {
+	if options != nil && options.OnSymlink != nil {
+		return options.OnSymlink(src)
+	}
+	if options != nil && options.Symlinks {
+		return Shallow
+	}
+	return Deep
+}
+
+// Recursively copy a directory tree.
+//
+// The destination directory must not already exist; if it does, CopyTree
+// returns a DirExistsError unless OnDirExists says otherwise.
+// If exception(s) occur, an Error is raised with a list of reasons.
+//
+// If the optional symlinks flag is true, symbolic links in the
+// source tree result in symbolic links in the destination tree; if
+// it is false, the contents of the files pointed to by symbolic
+// links are copied. If the file pointed by the symlink doesn't
+// exist, an exception will be added in the list of errors raised in
+// an Error exception at the end of the copy process. For finer-grained
+// control over this behavior on a per-entry basis, set OnSymlink instead.
+//
+// You can set the optional ignore_dangling_symlinks flag to true if you
+// want to silence this exception. Notice that this has no effect on
+// platforms that don't support os.symlink.
+//
+// The optional ignore argument is a callable. If given, it
+// is called with the `src` parameter, which is the directory
+// being visited by copytree(), and `names` which is the list of
+// `src` contents, as returned by os.listdir():
+//
+//     callable(src, names) -> ignored_names
+//
+// Since copytree() is called recursively, the callable will be
+// called once for each directory that is copied. It returns a
+// list of names relative to the `src` directory that should
+// not be copied.
+//
+// The optional copy_function argument is a callable that will be used
+// to copy each file. It will be called with the source path and the
+// destination path as arguments. By default, copy2() is used, but any
+// function that supports the same signature (like copy()) can be used.
+//
+// CopyTree operates on the real, local filesystem; use CopyTreeFS to
+// copy to or from a different FileSystem/WritableFileSystem.
+func CopyTree(src, dst string, options *CopyTreeOptions) (string, error) {
+	return CopyTreeFS(OSFileSystem{}, OSFileSystem{}, src, dst, options)
+}
+
+// CopyTreeFS is CopyTree, reading through srcFS and writing through dstFS.
+func CopyTreeFS(srcFS FileSystem, dstFS WritableFileSystem, src, dst string, options *CopyTreeOptions) (string, error) {
+	copyFunction := func(s, d string, o *CopyOptions) (string, error) {
+		return Copy2FS(srcFS, dstFS, s, d, o)
+	}
+	if options != nil && options.CopyFunction != nil {
+		copyFunction = options.CopyFunction
+	}
+
+	srcInfo, err := srcFS.Stat(src)
+	if err != nil {
+		return "", err
+	}
+
+	subs, err := srcFS.ReadDir(src)
+	if err != nil {
+		return "", err
+	}
+
+	ignoredNames := []string{}
+	if options != nil && options.Ignore != nil {
+		ignoredNames = options.Ignore(src, subs)
+	}
+
+	if dstInfo, err := statIfPossible(dstFS, dst); err == nil && dstInfo.IsDir() {
+		switch dirExistsAction(options, src, dst) {
+		case Fail:
+			return "", &DirExistsError{dst}
+		case Replace:
+			if err := rmTreeFS(dstFS, dst); err != nil {
+				return "", err
+			}
+		case Merge:
+			// Fall through: copy children into the existing directory.
+		}
+	}
+
+	err = dstFS.Mkdir(dst, srcInfo.Mode())
+	if err != nil {
+		return "", err
+	}
+
+	var treeErrors []error
+
+	for _, sub := range subs {
+		isIgnored := false
+		for _, ignoredName := range ignoredNames {
+			if sub.Name() == ignoredName {
+				isIgnored = true
+				break
+			}
+		}
+
+		if isIgnored {
+			continue
+		}
+
+		subSrc := fsPathJoin(srcFS, src, sub.Name())
+		subDst := fsPathJoin(dstFS, dst, sub.Name())
+
+		subSrcInfo, err := srcFS.Lstat(subSrc)
+		if err != nil {
+			return "", err
+		}
+
+		if options != nil && options.PreCallback != nil {
+			if err := options.PreCallback(subSrc, subDst, subSrcInfo); err != nil {
+				if err == ErrSkip {
+					continue
+				}
+				return "", err
+			}
+		}
+
+		if (subSrcInfo.Mode() & os.ModeSymlink) == os.ModeSymlink {
+			switch symlinkAction(options, subSrc) {
+			case Skip:
+				continue
+			case Shallow:
+				linkTarget, err := srcFS.Readlink(subSrc)
+				if err != nil {
+					return "", err
+				}
+				// Lstat, not statIfPossible: a dangling symlink already at
+				// subDst must still be detected and removed, even though
+				// Stat on it would report os.ErrNotExist.
+				if _, err := lstatIfPossible(dstFS, subDst); err == nil {
+					if err := removeIfPossible(dstFS, subDst); err != nil {
+						return "", err
+					}
+				}
+				if err := dstFS.Symlink(linkTarget, subDst); err != nil {
+					return "", err
+				}
+			case Deep:
+				targetInfo, err := srcFS.Stat(subSrc)
+				if err != nil {
+					if os.IsNotExist(err) {
+						if options == nil || !options.IgnoreDanglingSymlinks {
+							treeErrors = append(treeErrors, &DanglingSymlinkError{subSrc})
+						}
+						continue
+					}
+					return "", err
+				}
+				if targetInfo.IsDir() {
+					_, err = CopyTreeFS(srcFS, dstFS, subSrc, subDst, options)
+				} else {
+					_, err = copyFunction(subSrc, subDst, nil)
+				}
+				if err != nil {
+					if te, ok := err.(*TreeError); ok {
+						treeErrors = append(treeErrors, te.Errors...)
+					} else {
+						return "", err
+					}
+				}
+			}
+		} else if subSrcInfo.IsDir() {
+			_, err = CopyTreeFS(srcFS, dstFS, subSrc, subDst, options)
+			if err != nil {
+				if te, ok := err.(*TreeError); ok {
+					treeErrors = append(treeErrors, te.Errors...)
+				} else {
+					return "", err
+				}
+			}
+		} else {
+			_, err = copyFunction(subSrc, subDst, nil)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		if options != nil && options.PostCallback != nil {
+			if err := options.PostCallback(subSrc, subDst, subSrcInfo); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if err := copyStatFS(srcFS, dstFS, src, dst, nil); err != nil {
+		return "", err
+	}
+
+	if len(treeErrors) > 0 {
+		return dst, &TreeError{treeErrors}
+	}
+
+	return dst, nil
+}
+
+// rmTreeFS removes dst and everything beneath it through dstFS, for
+// DirExistsAction Replace. When dstFS is the real, local filesystem this
+// is RmTree; otherwise it requires dstFS to opportunistically support
+// RemoveAll.
+func rmTreeFS(dstFS WritableFileSystem, dst string) error {
+	if _, ok := dstFS.(OSFileSystem); ok {
+		return RmTree(dst, nil)
+	}
+	if r, ok := dstFS.(interface {
+		RemoveAll(string) error
+	}); ok {
+		return r.RemoveAll(dst)
+	}
+	return &DirExistsError{dst}
+}
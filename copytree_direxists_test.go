@@ -0,0 +1,93 @@
+package shutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		full := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCopyTreeOnDirExistsFail(t *testing.T) {
+	src := t.TempDir()
+	makeTree(t, src, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+
+	dstRoot := t.TempDir()
+	dst := filepath.Join(dstRoot, "tree")
+	if err := os.Mkdir(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := CopyTree(src, dst, nil)
+	if _, ok := err.(*DirExistsError); !ok {
+		t.Fatalf("CopyTree error = %v, want *DirExistsError", err)
+	}
+}
+
+func TestCopyTreeOnDirExistsMerge(t *testing.T) {
+	src := t.TempDir()
+	makeTree(t, src, map[string]string{"a.txt": "new-a", "sub/b.txt": "new-b"})
+
+	dstRoot := t.TempDir()
+	dst := filepath.Join(dstRoot, "tree")
+	makeTree(t, dst, map[string]string{"a.txt": "old-a", "sub/c.txt": "old-c"})
+
+	options := &CopyTreeOptions{
+		OnDirExists: func(src, dst string) DirExistsAction { return Merge },
+	}
+	if _, err := CopyTree(src, dst, options); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+
+	for rel, want := range map[string]string{
+		"a.txt":     "new-a",
+		"sub/b.txt": "new-b",
+		"sub/c.txt": "old-c",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, rel))
+		if err != nil {
+			t.Fatalf("ReadFile(%s): %v", rel, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestCopyTreeOnDirExistsReplace(t *testing.T) {
+	src := t.TempDir()
+	makeTree(t, src, map[string]string{"a.txt": "new-a"})
+
+	dstRoot := t.TempDir()
+	dst := filepath.Join(dstRoot, "tree")
+	makeTree(t, dst, map[string]string{"a.txt": "old-a", "sub/stale.txt": "stale"})
+
+	options := &CopyTreeOptions{
+		OnDirExists: func(src, dst string) DirExistsAction { return Replace },
+	}
+	if _, err := CopyTree(src, dst, options); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dst, "sub", "stale.txt")); !os.IsNotExist(err) {
+		t.Fatalf("stale.txt should have been removed by Replace, err=%v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dst, "a.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile(a.txt): %v", err)
+	}
+	if string(got) != "new-a" {
+		t.Fatalf("a.txt = %q, want %q", got, "new-a")
+	}
+}
@@ -0,0 +1,44 @@
+package shutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyTreeShallowSymlinkReplacesDanglingDst(t *testing.T) {
+	srcRoot := t.TempDir()
+	dstRoot := t.TempDir()
+	dst := filepath.Join(dstRoot, "tree")
+
+	target := filepath.Join(srcRoot, "target")
+	if err := os.WriteFile(target, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(srcRoot, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dst, "does-not-exist"), filepath.Join(dst, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	options := &CopyTreeOptions{
+		Symlinks:    true,
+		OnDirExists: func(src, dst string) DirExistsAction { return Merge },
+	}
+	if _, err := CopyTree(srcRoot, dst, options); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+
+	got, err := os.Readlink(filepath.Join(dst, "link"))
+	if err != nil {
+		t.Fatalf("Readlink: %v", err)
+	}
+	if got != target {
+		t.Fatalf("link target = %q, want %q", got, target)
+	}
+}
@@ -1,6 +1,15 @@
 package shutil
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSkip, when returned by a PreCallback, tells CopyFile or CopyTree to
+// omit that entry (and, for a directory, everything beneath it) without
+// aborting the rest of the copy.
+var ErrSkip = errors.New("shutil: skip")
 
 type SameFileError struct {
 	Src string
@@ -27,3 +36,43 @@ type CopyNotCompleteError struct {
 func (e CopyNotCompleteError) Error() string {
 	return fmt.Sprintf("copy %s to %s not complete", e.Src, e.Dst)
 }
+
+// DirExistsError is returned by CopyTree when the destination directory
+// already exists and CopyTreeOptions.OnDirExists resolves to Fail (the
+// default), preserving CopyTree's documented "must not already exist"
+// contract.
+type DirExistsError struct {
+	Dst string
+}
+
+func (e DirExistsError) Error() string {
+	return fmt.Sprintf("%s already exists", e.Dst)
+}
+
+// DanglingSymlinkError indicates that a symlink's target does not exist.
+// It is collected into a TreeError rather than aborting CopyTree, unless
+// CopyTreeOptions.IgnoreDanglingSymlinks silences it entirely.
+type DanglingSymlinkError struct {
+	Src string
+}
+
+func (e DanglingSymlinkError) Error() string {
+	return fmt.Sprintf("%s is a dangling symlink", e.Src)
+}
+
+// TreeError aggregates one or more non-fatal errors encountered while
+// walking a tree, in the manner of Python's shutil.Error. CopyTree collects
+// DanglingSymlinkErrors into it and carries on with the walk, reporting
+// them together at the end; any other failure aborts the copy at once and
+// is returned as is.
+type TreeError struct {
+	Errors []error
+}
+
+func (e *TreeError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
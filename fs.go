@@ -0,0 +1,155 @@
+package shutil
+
+import (
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// FileSystem is the read side of the filesystem abstraction CopyFS,
+// Copy2FS and CopyTreeFS operate over. OSFileSystem implements it in
+// terms of the real, local filesystem, which is what CopyFile, Copy2 and
+// CopyTree use by default. Anything satisfying the standard io/fs.FS can
+// be adapted to it with FromFS.
+type FileSystem interface {
+	Open(name string) (io.ReadCloser, error)
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	ReadDir(name string) ([]os.FileInfo, error)
+	Readlink(name string) (string, error)
+}
+
+// WritableFileSystem is the write side of the abstraction: the
+// destination CopyFS, Copy2FS and CopyTreeFS write into.
+type WritableFileSystem interface {
+	Create(name string) (io.WriteCloser, error)
+	Mkdir(name string, perm os.FileMode) error
+	Symlink(oldname, newname string) error
+	Chmod(name string, mode os.FileMode) error
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+// OSFileSystem implements FileSystem and WritableFileSystem over the real,
+// local filesystem.
+type OSFileSystem struct{}
+
+func (OSFileSystem) Open(name string) (io.ReadCloser, error)   { return os.Open(name) }
+func (OSFileSystem) Stat(name string) (os.FileInfo, error)     { return os.Stat(name) }
+func (OSFileSystem) Lstat(name string) (os.FileInfo, error)    { return os.Lstat(name) }
+func (OSFileSystem) ReadDir(name string) ([]os.FileInfo, error) { return ioutil.ReadDir(name) }
+func (OSFileSystem) Readlink(name string) (string, error)      { return os.Readlink(name) }
+
+func (OSFileSystem) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (OSFileSystem) Mkdir(name string, perm os.FileMode) error  { return os.MkdirAll(name, perm) }
+func (OSFileSystem) Symlink(oldname, newname string) error      { return os.Symlink(oldname, newname) }
+func (OSFileSystem) Chmod(name string, mode os.FileMode) error  { return os.Chmod(name, mode) }
+func (OSFileSystem) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+// Remove and RemoveAll aren't part of WritableFileSystem, since not every
+// destination can support them, but CopyTreeFS uses them opportunistically
+// (via a type assertion): Remove to recreate an existing symlink, and
+// RemoveAll to honor DirExistsAction Replace on destinations other than
+// OSFileSystem, for which Replace goes through RmTree instead.
+func (OSFileSystem) Remove(name string) error    { return os.Remove(name) }
+func (OSFileSystem) RemoveAll(name string) error { return os.RemoveAll(name) }
+
+type fsAdapter struct {
+	fsys fs.FS
+}
+
+// FromFS adapts fsys to FileSystem for use as the source of CopyFS,
+// Copy2FS or CopyTreeFS, so copying out of an embed.FS, a zip archive, or
+// any other io/fs.FS needs no custom type. Symlinks aren't representable
+// in io/fs.FS, so Readlink on the result always fails.
+func FromFS(fsys fs.FS) FileSystem {
+	return fsAdapter{fsys}
+}
+
+func (a fsAdapter) Open(name string) (io.ReadCloser, error) { return a.fsys.Open(name) }
+
+func (a fsAdapter) Stat(name string) (os.FileInfo, error) { return fs.Stat(a.fsys, name) }
+
+// Lstat has no symlink-aware counterpart in io/fs.FS, so it's the same as Stat.
+func (a fsAdapter) Lstat(name string) (os.FileInfo, error) { return fs.Stat(a.fsys, name) }
+
+func (a fsAdapter) ReadDir(name string) ([]os.FileInfo, error) {
+	entries, err := fs.ReadDir(a.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]os.FileInfo, len(entries))
+	for i, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos[i] = info
+	}
+	return infos, nil
+}
+
+func (a fsAdapter) Readlink(name string) (string, error) {
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
+
+// fsPathJoin joins elem the way fsys expects its paths: filepath.Join
+// (native separators, volume-aware) for OSFileSystem, and path.Join
+// (always "/") for everything else, since fsAdapter and other
+// FileSystem/WritableFileSystem implementations use io/fs's slash-only
+// paths regardless of platform.
+func fsPathJoin(fsys interface{}, elem ...string) string {
+	if _, ok := fsys.(OSFileSystem); ok {
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+// fsPathBase is Base, chosen the same way fsPathJoin chooses Join.
+func fsPathBase(fsys interface{}, p string) string {
+	if _, ok := fsys.(OSFileSystem); ok {
+		return filepath.Base(p)
+	}
+	return path.Base(p)
+}
+
+// statIfPossible stats name on fsys if fsys knows how to, and reports
+// os.ErrNotExist otherwise. It lets CopyFS/CopyTreeFS make a best effort
+// at existing-destination checks without requiring every WritableFileSystem
+// to implement Stat.
+func statIfPossible(fsys interface{}, name string) (os.FileInfo, error) {
+	if s, ok := fsys.(interface {
+		Stat(string) (os.FileInfo, error)
+	}); ok {
+		return s.Stat(name)
+	}
+	return nil, os.ErrNotExist
+}
+
+// lstatIfPossible lstats name on fsys if fsys knows how to, and reports
+// os.ErrNotExist otherwise. Unlike statIfPossible, it doesn't follow
+// symlinks, so a dangling symlink at name is still reported as existing.
+func lstatIfPossible(fsys interface{}, name string) (os.FileInfo, error) {
+	if s, ok := fsys.(interface {
+		Lstat(string) (os.FileInfo, error)
+	}); ok {
+		return s.Lstat(name)
+	}
+	return nil, os.ErrNotExist
+}
+
+// removeIfPossible removes name on fsys if fsys knows how to, and is a
+// no-op otherwise.
+func removeIfPossible(fsys interface{}, name string) error {
+	if r, ok := fsys.(interface {
+		Remove(string) error
+	}); ok {
+		return r.Remove(name)
+	}
+	return nil
+}
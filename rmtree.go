@@ -0,0 +1,113 @@
+package shutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type RmTreeOptions struct {
+	IgnoreErrors bool
+	OnError      func(fn func(string), path string, exec_info interface{})
+}
+
+// Recursively delete a directory tree.
+//
+// If ignore_errors is set, errors are ignored; otherwise, if onerror
+// is set, it is called to handle the error with arguments (func,
+// path, exc_info) where func is platform and implementation dependent;
+// path is the argument to that function that caused it to fail; and
+// exc_info is the error it failed with.  If ignore_errors
+// is false and onerror is None, an exception is raised.
+func RmTree(path string, options *RmTreeOptions) error {
+	ignoreErrors := false
+	var onError func(fn func(string), path string, exec_info interface{})
+	if options != nil {
+		ignoreErrors = options.IgnoreErrors
+		onError = options.OnError
+	}
+
+	handle := func(fn func(string), failedPath string, err error) error {
+		if ignoreErrors {
+			return nil
+		}
+		if onError != nil {
+			onError(fn, failedPath, err)
+			return nil
+		}
+		return err
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return handle(func(p string) { os.Remove(p) }, path, err)
+	}
+
+	if info.Mode()&os.ModeSymlink == os.ModeSymlink || !info.IsDir() {
+		if err := tryRemove(filepath.Dir(path), path); err != nil {
+			return handle(func(p string) { os.Remove(p) }, path, err)
+		}
+		return nil
+	}
+
+	return rmTreeDir(path, handle)
+}
+
+// rmTreeDir removes dir and everything beneath it in post-order: children
+// are removed before their parent, so a partially-failed tree leaves only
+// the entries that actually failed.
+func rmTreeDir(dir string, handle func(fn func(string), path string, err error) error) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil && os.IsPermission(err) {
+		if chmodErr := os.Chmod(dir, 0700); chmodErr == nil {
+			entries, err = ioutil.ReadDir(dir)
+		}
+	}
+	if err != nil {
+		return handle(func(p string) { os.RemoveAll(p) }, dir, err)
+	}
+
+	for _, entry := range entries {
+		sub := filepath.Join(dir, entry.Name())
+
+		info, err := os.Lstat(sub)
+		if err != nil {
+			if herr := handle(func(p string) { os.Remove(p) }, sub, err); herr != nil {
+				return herr
+			}
+			continue
+		}
+
+		if info.Mode()&os.ModeSymlink != os.ModeSymlink && info.IsDir() {
+			if err := rmTreeDir(sub, handle); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := tryRemove(dir, sub); err != nil {
+			if herr := handle(func(p string) { os.Remove(p) }, sub, err); herr != nil {
+				return herr
+			}
+		}
+	}
+
+	if err := tryRemove(filepath.Dir(dir), dir); err != nil {
+		return handle(func(p string) { os.Remove(p) }, dir, err)
+	}
+
+	return nil
+}
+
+// tryRemove removes path, and if that fails because the containing dir
+// isn't writable, chmods dir to 0700 and retries once. This mirrors
+// Python's shutil.rmtree, which recovers from read-only trees the same way.
+func tryRemove(dir, path string) error {
+	err := os.Remove(path)
+	if err != nil && os.IsPermission(err) {
+		if chmodErr := os.Chmod(dir, 0700); chmodErr == nil {
+			err = os.Remove(path)
+		}
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package shutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRmTreeReadOnlySubtree(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(sub, "file")
+	if err := os.WriteFile(file, []byte("data"), 0400); err != nil {
+		t.Fatal(err)
+	}
+	// Create sub writable so the file can be written into it, then lock
+	// it read-only so RmTree has to restore write permission to empty it.
+	if err := os.Chmod(sub, 0500); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RmTree(root, nil); err != nil {
+		t.Fatalf("RmTree: %v", err)
+	}
+
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("root still exists after RmTree: err=%v", err)
+	}
+}
+
+func TestRmTreeDanglingSymlink(t *testing.T) {
+	root := t.TempDir()
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(filepath.Join(root, "does-not-exist"), link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RmTree(link, nil); err != nil {
+		t.Fatalf("RmTree on dangling symlink: %v", err)
+	}
+
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Fatalf("link still exists after RmTree: err=%v", err)
+	}
+}
+
+func TestRmTreeDanglingSymlinkInsideTree(t *testing.T) {
+	root := t.TempDir()
+	link := filepath.Join(root, "sub", "link")
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "sub", "does-not-exist"), link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RmTree(root, nil); err != nil {
+		t.Fatalf("RmTree: %v", err)
+	}
+
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("root still exists after RmTree: err=%v", err)
+	}
+}